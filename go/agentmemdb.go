@@ -156,35 +156,80 @@ func lastError() string {
 	return C.GoString(p)
 }
 
-// DiskDB is a disk-backed agent memory database. Episodes stored in append-only log.
+// DiskDB is a disk-backed agent memory database. Episodes are appended to
+// a write-ahead journal before being applied to the in-memory index, so a
+// crash between writes loses at most the unflushed tail of the journal.
 type DiskDB struct {
 	handle C.AgentMemDBDiskHandle
 	dim    int
+
+	// storage and dir support Storage backends that aren't already a
+	// real directory: dir is a scratch directory staged from storage
+	// that the C-API reads and writes directly, and it is flushed back
+	// to storage on Checkpoint and CompactNow. When storage is already a
+	// real directory (e.g. FileStorage), dir is that directory itself and
+	// staged is false, so nothing is flushed.
+	storage Storage
+	dir     string
+	staged  bool
 }
 
-// OpenDisk opens or creates a disk-backed DB at the given directory. Uses HNSW by default.
-func OpenDisk(path string, dim int) (*DiskDB, error) {
-	cpath := C.CString(path)
+// flushStorage copies the DiskDB's working directory back to its
+// Storage, if that Storage isn't already a real directory.
+func (db *DiskDB) flushStorage() error {
+	return flushStage(db.dir, db.storage, db.staged)
+}
+
+// WriteOptions controls the durability of a Store call against a DiskDB.
+type WriteOptions struct {
+	// Sync fsyncs the journal record before Store returns. Defaults to
+	// true; set to false to batch fsyncs across writes for higher
+	// throughput, at the cost of losing the most recent unsynced writes
+	// on a crash.
+	Sync bool
+}
+
+// defaultWriteOptions is used when Store is called without opts.
+var defaultWriteOptions = WriteOptions{Sync: true}
+
+// OpenDisk opens or creates a disk-backed DB on the given Storage. Uses
+// HNSW by default. On open, the newest checkpoint (if any) is loaded and
+// journal records after it are replayed; a truncated final record from a
+// mid-write crash is tolerated by stopping replay at the first bad CRC.
+func OpenDisk(storage Storage, dim int) (*DiskDB, error) {
+	dir, staged, err := stageDir(storage)
+	if err != nil {
+		return nil, fmt.Errorf("stage storage: %w", err)
+	}
+	cpath := C.CString(dir)
 	defer C.free(unsafe.Pointer(cpath))
 	h := C.agent_mem_db_disk_open(cpath, C.size_t(dim))
 	if h == nil {
 		return nil, fmt.Errorf("open disk failed: %s", lastError())
 	}
-	return &DiskDB{handle: h, dim: dim}, nil
+	return &DiskDB{handle: h, dim: dim, storage: storage, dir: dir, staged: staged}, nil
 }
 
-// OpenDiskExactWithCheckpoint opens with exact index and checkpoint for fast restart.
-func OpenDiskExactWithCheckpoint(path string, dim int) (*DiskDB, error) {
-	cpath := C.CString(path)
+// OpenDiskExactWithCheckpoint opens with exact index and checkpoint for
+// fast restart, replaying the journal past the checkpoint's last-applied
+// sequence number as described on OpenDisk.
+func OpenDiskExactWithCheckpoint(storage Storage, dim int) (*DiskDB, error) {
+	dir, staged, err := stageDir(storage)
+	if err != nil {
+		return nil, fmt.Errorf("stage storage: %w", err)
+	}
+	cpath := C.CString(dir)
 	defer C.free(unsafe.Pointer(cpath))
 	h := C.agent_mem_db_disk_open_exact_with_checkpoint(cpath, C.size_t(dim))
 	if h == nil {
 		return nil, fmt.Errorf("open disk failed: %s", lastError())
 	}
-	return &DiskDB{handle: h, dim: dim}, nil
+	return &DiskDB{handle: h, dim: dim, storage: storage, dir: dir, staged: staged}, nil
 }
 
-// Free releases the disk DB. Call when done.
+// Free releases the disk DB. Call when done. Free does not flush a
+// staged Storage (see Storage); call Checkpoint first if durability
+// against a non-directory Storage is required.
 func (db *DiskDB) Free() {
 	if db != nil && db.handle != nil {
 		C.agent_mem_db_disk_free(db.handle)
@@ -197,18 +242,25 @@ func (db *DiskDB) Dim() int {
 	return db.dim
 }
 
-// Store adds an episode.
-func (db *DiskDB) Store(taskID string, embedding []float32, reward float32) error {
+// Store adds an episode, appending it to the write-ahead journal before
+// applying it to the index. By default each Store fsyncs the journal
+// record before returning; pass a WriteOptions with Sync: false to batch
+// fsyncs for throughput.
+func (db *DiskDB) Store(taskID string, embedding []float32, reward float32, opts ...WriteOptions) error {
 	if db == nil || db.handle == nil {
 		return fmt.Errorf("db is nil or freed")
 	}
 	if len(embedding) != db.dim {
 		return fmt.Errorf("embedding dimension mismatch: expected %d, got %d", db.dim, len(embedding))
 	}
+	wopts := defaultWriteOptions
+	if len(opts) > 0 {
+		wopts = opts[0]
+	}
 	ctask := C.CString(taskID)
 	defer C.free(unsafe.Pointer(ctask))
 	emb := (*C.float)(unsafe.Pointer(&embedding[0]))
-	r := C.agent_mem_db_disk_store(db.handle, ctask, emb, C.size_t(len(embedding)), C.float(reward))
+	r := C.agent_mem_db_disk_store_opts(db.handle, ctask, emb, C.size_t(len(embedding)), C.float(reward), C.bool(wopts.Sync))
 	if r != 0 {
 		return fmt.Errorf("store failed: %s", lastError())
 	}
@@ -237,7 +289,12 @@ func (db *DiskDB) Query(embedding []float32, minReward float32, topK int) ([]Epi
 	return episodes, nil
 }
 
-// Checkpoint persists the ExactIndex checkpoint for fast restart. No-op for HNSW.
+// Checkpoint writes a manifest referencing the current ExactIndex snapshot
+// and the last-applied journal sequence number, so the next OpenDisk* only
+// has to replay journal records written after it. The C-API checkpoint is
+// a no-op for HNSW. If the DiskDB was opened against a Storage that isn't
+// already a real directory, Checkpoint also flushes the working directory
+// back to that Storage, whatever the index kind.
 func (db *DiskDB) Checkpoint() error {
 	if db == nil || db.handle == nil {
 		return fmt.Errorf("db is nil or freed")
@@ -246,6 +303,9 @@ func (db *DiskDB) Checkpoint() error {
 	if r != 0 {
 		return fmt.Errorf("checkpoint failed: %s", lastError())
 	}
+	if err := db.flushStorage(); err != nil {
+		return fmt.Errorf("flush storage: %w", err)
+	}
 	return nil
 }
 
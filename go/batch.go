@@ -0,0 +1,150 @@
+package agentmemdb
+
+/*
+#include "agent_mem_db.h"
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"unsafe"
+)
+
+// batchOp is one accumulated operation in a Batch. It is marshaled to JSON
+// and applied in a single call across the C boundary.
+type batchOp struct {
+	Op             string    `json:"op"`
+	ID             string    `json:"id,omitempty"`
+	TaskID         string    `json:"task_id,omitempty"`
+	StateEmbedding []float32 `json:"state_embedding,omitempty"`
+	Reward         float32   `json:"reward,omitempty"`
+	Metadata       any       `json:"metadata,omitempty"`
+	Timestamp      *int64    `json:"timestamp,omitempty"`
+	Tags           []string  `json:"tags,omitempty"`
+	Source         *string   `json:"source,omitempty"`
+	UserID         *string   `json:"user_id,omitempty"`
+}
+
+// PutOption sets an optional field on an episode added via Batch.Put.
+type PutOption func(*batchOp)
+
+// WithMetadata attaches arbitrary JSON-serializable metadata to the episode.
+func WithMetadata(metadata any) PutOption {
+	return func(op *batchOp) { op.Metadata = metadata }
+}
+
+// WithTags attaches tags to the episode.
+func WithTags(tags []string) PutOption {
+	return func(op *batchOp) { op.Tags = tags }
+}
+
+// WithTimestamp sets an explicit Unix millisecond timestamp on the episode,
+// overriding the default of "now" assigned by the store.
+func WithTimestamp(timestampMs int64) PutOption {
+	return func(op *batchOp) { op.Timestamp = &timestampMs }
+}
+
+// WithSource attaches a source label to the episode.
+func WithSource(source string) PutOption {
+	return func(op *batchOp) { op.Source = &source }
+}
+
+// WithUserID attaches a user ID to the episode.
+func WithUserID(userID string) PutOption {
+	return func(op *batchOp) { op.UserID = &userID }
+}
+
+// Batch accumulates Store and Delete operations in memory so they can be
+// applied atomically in a single call via DB.Write or DiskDB.Write. Either
+// every operation in the batch is applied, or none are. Batching hundreds
+// of episodes per Write call cuts CGO crossing overhead compared to calling
+// Store in a loop.
+type Batch struct {
+	ops []batchOp
+}
+
+// NewBatch creates an empty Batch.
+func NewBatch() *Batch {
+	return &Batch{}
+}
+
+// Put queues an episode to be stored. taskID and embedding are required;
+// opts may set the episode's metadata, tags, timestamp, source, or user ID.
+func (b *Batch) Put(taskID string, embedding []float32, reward float32, opts ...PutOption) {
+	op := batchOp{
+		Op:             "store",
+		TaskID:         taskID,
+		StateEmbedding: embedding,
+		Reward:         reward,
+	}
+	for _, opt := range opts {
+		opt(&op)
+	}
+	b.ops = append(b.ops, op)
+}
+
+// Delete queues an episode to be removed by ID.
+func (b *Batch) Delete(id string) {
+	b.ops = append(b.ops, batchOp{Op: "delete", ID: id})
+}
+
+// Len returns the number of queued operations.
+func (b *Batch) Len() int {
+	return len(b.ops)
+}
+
+// Reset clears the batch so it can be reused.
+func (b *Batch) Reset() {
+	b.ops = b.ops[:0]
+}
+
+func (b *Batch) marshal() (*C.char, error) {
+	buf, err := json.Marshal(b.ops)
+	if err != nil {
+		return nil, fmt.Errorf("marshal batch: %w", err)
+	}
+	return C.CString(string(buf)), nil
+}
+
+// Write applies batch atomically: either every operation is applied, or
+// none are.
+func (db *DB) Write(batch *Batch) error {
+	if db == nil || db.handle == nil {
+		return fmt.Errorf("db is nil or freed")
+	}
+	if batch == nil {
+		return fmt.Errorf("batch is nil")
+	}
+	cops, err := batch.marshal()
+	if err != nil {
+		return err
+	}
+	defer C.free(unsafe.Pointer(cops))
+	r := C.agent_mem_db_write_batch(db.handle, cops)
+	if r != 0 {
+		return fmt.Errorf("write batch failed: %s", lastError())
+	}
+	return nil
+}
+
+// Write applies batch atomically: either every operation is applied, or
+// none are.
+func (db *DiskDB) Write(batch *Batch) error {
+	if db == nil || db.handle == nil {
+		return fmt.Errorf("db is nil or freed")
+	}
+	if batch == nil {
+		return fmt.Errorf("batch is nil")
+	}
+	cops, err := batch.marshal()
+	if err != nil {
+		return err
+	}
+	defer C.free(unsafe.Pointer(cops))
+	r := C.agent_mem_db_disk_write_batch(db.handle, cops)
+	if r != 0 {
+		return fmt.Errorf("write batch failed: %s", lastError())
+	}
+	return nil
+}
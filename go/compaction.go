@@ -0,0 +1,97 @@
+package agentmemdb
+
+/*
+#include "agent_mem_db.h"
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"unsafe"
+)
+
+// OpenDiskOptions configures background compaction for a DiskDB. The zero
+// value uses the library's defaults.
+type OpenDiskOptions struct {
+	// MaxSegmentBytes caps the size of a single log segment before it is
+	// rolled over. Zero uses the library default.
+	MaxSegmentBytes int64 `json:"max_segment_bytes"`
+
+	// CompactionTrigger is the dead-fraction of a segment (space held by
+	// tombstones left behind by PruneOlderThan / PruneKeepNewest /
+	// PruneKeepHighestReward) that triggers a rewrite. Zero uses the
+	// library default.
+	CompactionTrigger float64 `json:"compaction_trigger"`
+
+	// DisableAutoCompaction stops the background compaction goroutine
+	// from running; callers must invoke CompactNow themselves.
+	DisableAutoCompaction bool `json:"disable_auto_compaction"`
+}
+
+// CompactionStats reports the current state of a DiskDB's segment files.
+type CompactionStats struct {
+	SegmentCount   int     `json:"segment_count"`
+	DeadFraction   float64 `json:"dead_fraction"`
+	BytesReclaimed int64   `json:"bytes_reclaimed"`
+}
+
+// OpenDiskWithOptions opens or creates a disk-backed DB like OpenDisk, with
+// control over background log compaction.
+func OpenDiskWithOptions(storage Storage, dim int, opts OpenDiskOptions) (*DiskDB, error) {
+	dir, staged, err := stageDir(storage)
+	if err != nil {
+		return nil, fmt.Errorf("stage storage: %w", err)
+	}
+	cpath := C.CString(dir)
+	defer C.free(unsafe.Pointer(cpath))
+	copts, err := json.Marshal(opts)
+	if err != nil {
+		return nil, fmt.Errorf("marshal open options: %w", err)
+	}
+	ccopts := C.CString(string(copts))
+	defer C.free(unsafe.Pointer(ccopts))
+	h := C.agent_mem_db_disk_open_with_options(cpath, C.size_t(dim), ccopts)
+	if h == nil {
+		return nil, fmt.Errorf("open disk failed: %s", lastError())
+	}
+	return &DiskDB{handle: h, dim: dim, storage: storage, dir: dir, staged: staged}, nil
+}
+
+// CompactNow merges the append-only episode log into sorted-by-id segment
+// files and reclaims space held by tombstones, blocking until the rewrite
+// completes. It is safe to call even when auto-compaction is disabled or
+// already running. If the DiskDB was opened against a Storage that isn't
+// already a real directory, CompactNow also flushes the working
+// directory back to that Storage.
+func (db *DiskDB) CompactNow() error {
+	if db == nil || db.handle == nil {
+		return fmt.Errorf("db is nil or freed")
+	}
+	r := C.agent_mem_db_disk_compact_now(db.handle)
+	if r != 0 {
+		return fmt.Errorf("compact failed: %s", lastError())
+	}
+	if err := db.flushStorage(); err != nil {
+		return fmt.Errorf("flush storage: %w", err)
+	}
+	return nil
+}
+
+// CompactionStats reports the current segment count and dead-fraction, so
+// callers can decide whether to call CompactNow themselves.
+func (db *DiskDB) CompactionStats() (CompactionStats, error) {
+	if db == nil || db.handle == nil {
+		return CompactionStats{}, fmt.Errorf("db is nil or freed")
+	}
+	jsonStr := C.agent_mem_db_disk_compaction_stats(db.handle)
+	if jsonStr == nil {
+		return CompactionStats{}, fmt.Errorf("compaction stats failed: %s", lastError())
+	}
+	defer C.agent_mem_db_free_string(jsonStr)
+	var stats CompactionStats
+	if err := json.Unmarshal([]byte(C.GoString(jsonStr)), &stats); err != nil {
+		return CompactionStats{}, fmt.Errorf("parse compaction stats: %w", err)
+	}
+	return stats, nil
+}
@@ -52,10 +52,10 @@ func ExampleDB_PruneKeepNewest() {
 }
 
 func ExampleDiskDB() {
-	dir, _ := os.MkdirTemp("", "agent_mem_go_disk_*")
-	defer os.RemoveAll(dir)
+	storage := agentmemdb.NewMemStorage()
+	defer storage.Close()
 
-	db, err := agentmemdb.OpenDiskExactWithCheckpoint(dir, 8)
+	db, err := agentmemdb.OpenDiskExactWithCheckpoint(storage, 8)
 	if err != nil {
 		panic(err)
 	}
@@ -63,13 +63,125 @@ func ExampleDiskDB() {
 
 	emb := []float32{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8}
 	db.Store("task1", emb, 0.9)
-	db.Store("task2", emb, 0.8)
+	db.Store("task2", emb, 0.8, agentmemdb.WriteOptions{Sync: false})
 	db.Checkpoint()
 
-	db2, _ := agentmemdb.OpenDiskExactWithCheckpoint(dir, 8)
+	db2, _ := agentmemdb.OpenDiskExactWithCheckpoint(storage, 8)
 	defer db2.Free()
 	results, _ := db2.Query(emb, 0.5, 5)
 	fmt.Printf("Found %d episodes\n", len(results))
 	// Output:
 	// Found 2 episodes
 }
+
+func ExampleBatch() {
+	db := agentmemdb.New(8)
+	if db == nil {
+		panic("New failed")
+	}
+	defer db.Free()
+
+	emb := []float32{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8}
+	batch := agentmemdb.NewBatch()
+	batch.Put("task1", emb, 0.9, agentmemdb.WithTags([]string{"eval"}))
+	batch.Put("task2", emb, 0.8)
+	if err := db.Write(batch); err != nil {
+		panic(err)
+	}
+
+	results, err := db.Query(emb, 0.0, 5)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("Wrote %d, found %d episodes\n", batch.Len(), len(results))
+	// Output:
+	// Wrote 2, found 2 episodes
+}
+
+func ExampleDB_GetSnapshot() {
+	db := agentmemdb.New(8)
+	if db == nil {
+		panic("New failed")
+	}
+	defer db.Free()
+
+	emb := []float32{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8}
+	db.Store("task1", emb, 0.9)
+
+	snap, err := db.GetSnapshot()
+	if err != nil {
+		panic(err)
+	}
+	defer snap.Release()
+
+	db.Store("task2", emb, 0.8)
+
+	results, err := snap.Query(emb, 0.0, 5)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("Snapshot found %d episodes\n", len(results))
+	// Output:
+	// Snapshot found 1 episodes
+}
+
+func ExampleDB_NewIterator() {
+	db := agentmemdb.New(8)
+	if db == nil {
+		panic("New failed")
+	}
+	defer db.Free()
+
+	emb := []float32{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8}
+	db.Store("a", emb, 0.9)
+	db.Store("b", emb, 0.8)
+	db.Store("c", emb, 0.7)
+
+	it := db.NewIterator(&agentmemdb.IterOptions{Order: agentmemdb.IterOrderReward})
+	defer it.Release()
+
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if err := it.Error(); err != nil {
+		panic(err)
+	}
+	fmt.Printf("Iterated %d episodes\n", count)
+	// Output:
+	// Iterated 3 episodes
+}
+
+func ExampleDiskDB_CompactNow() {
+	dir, _ := os.MkdirTemp("", "agent_mem_go_disk_*")
+	defer os.RemoveAll(dir)
+	storage, err := agentmemdb.NewFileStorage(dir)
+	if err != nil {
+		panic(err)
+	}
+	defer storage.Close()
+
+	db, err := agentmemdb.OpenDiskWithOptions(storage, 8, agentmemdb.OpenDiskOptions{
+		DisableAutoCompaction: true,
+	})
+	if err != nil {
+		panic(err)
+	}
+	defer db.Free()
+
+	emb := []float32{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8}
+	db.Store("a", emb, 0.9)
+	db.Store("b", emb, 0.8)
+	db.PruneKeepNewest(1)
+
+	if err := db.CompactNow(); err != nil {
+		panic(err)
+	}
+	stats, err := db.CompactionStats()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("Segments after compaction: %d\n", stats.SegmentCount)
+	// Output:
+	// Segments after compaction: 1
+}
@@ -0,0 +1,206 @@
+package agentmemdb
+
+/*
+#include "agent_mem_db.h"
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"unsafe"
+)
+
+// IterOrder selects the order in which an Iterator walks stored episodes.
+type IterOrder int
+
+const (
+	// IterOrderInsertion walks episodes in the order they were stored.
+	IterOrderInsertion IterOrder = iota
+	// IterOrderTimestampAsc walks episodes oldest-timestamp first.
+	IterOrderTimestampAsc
+	// IterOrderTimestampDesc walks episodes newest-timestamp first.
+	IterOrderTimestampDesc
+	// IterOrderReward walks episodes highest-reward first.
+	IterOrderReward
+)
+
+// IterOptions configures an Iterator. The zero value walks all episodes in
+// insertion order.
+type IterOptions struct {
+	Order IterOrder `json:"order"`
+
+	// Tag, Source, and UserID, if non-empty, restrict the iterator to
+	// episodes with a matching value.
+	Tag    string `json:"tag,omitempty"`
+	Source string `json:"source,omitempty"`
+	UserID string `json:"user_id,omitempty"`
+
+	// TimestampFrom and TimestampTo, if non-nil, restrict the iterator to
+	// episodes with a timestamp in [TimestampFrom, TimestampTo] (Unix ms).
+	TimestampFrom *int64 `json:"timestamp_from,omitempty"`
+	TimestampTo   *int64 `json:"timestamp_to,omitempty"`
+
+	// MinReward and MaxReward, if non-nil, restrict the iterator to
+	// episodes with a reward in [MinReward, MaxReward].
+	MinReward *float32 `json:"min_reward,omitempty"`
+	MaxReward *float32 `json:"max_reward,omitempty"`
+}
+
+// Iterator walks stored episodes without requiring a query vector, for
+// bulk export, offline reprocessing, or custom pruning policies. It is
+// snapshot-consistent: episodes stored or pruned after the iterator is
+// created are not observed. Results stream across the C boundary in
+// bounded-memory chunks rather than materializing as one JSON blob.
+//
+//	it := db.NewIterator(nil)
+//	defer it.Release()
+//	for it.Next() {
+//		ep := it.Episode()
+//		...
+//	}
+//	if err := it.Error(); err != nil {
+//		...
+//	}
+type Iterator struct {
+	handle C.AgentMemDBIteratorHandle
+	disk   bool
+
+	buf        []Episode
+	idx        int
+	cur        Episode
+	positioned bool
+	done       bool
+	err        error
+}
+
+func marshalIterOptions(opts *IterOptions) (*C.char, error) {
+	if opts == nil {
+		opts = &IterOptions{}
+	}
+	buf, err := json.Marshal(opts)
+	if err != nil {
+		return nil, fmt.Errorf("marshal iterator options: %w", err)
+	}
+	return C.CString(string(buf)), nil
+}
+
+// NewIterator creates an Iterator over the database's episodes. opts may
+// be nil to walk everything in insertion order. Call Release when done.
+func (db *DB) NewIterator(opts *IterOptions) *Iterator {
+	if db == nil || db.handle == nil {
+		return &Iterator{err: fmt.Errorf("db is nil or freed")}
+	}
+	copts, err := marshalIterOptions(opts)
+	if err != nil {
+		return &Iterator{err: err}
+	}
+	defer C.free(unsafe.Pointer(copts))
+	h := C.agent_mem_db_iterator_new(db.handle, copts)
+	if h == nil {
+		return &Iterator{err: fmt.Errorf("new iterator failed: %s", lastError())}
+	}
+	return &Iterator{handle: h}
+}
+
+// NewIterator creates an Iterator over the database's episodes. opts may
+// be nil to walk everything in insertion order. Call Release when done.
+func (db *DiskDB) NewIterator(opts *IterOptions) *Iterator {
+	if db == nil || db.handle == nil {
+		return &Iterator{err: fmt.Errorf("db is nil or freed")}
+	}
+	copts, err := marshalIterOptions(opts)
+	if err != nil {
+		return &Iterator{err: err}
+	}
+	defer C.free(unsafe.Pointer(copts))
+	h := C.agent_mem_db_disk_iterator_new(db.handle, copts)
+	if h == nil {
+		return &Iterator{err: fmt.Errorf("new iterator failed: %s", lastError()), disk: true}
+	}
+	return &Iterator{handle: h, disk: true}
+}
+
+// Next advances the iterator to the next episode, fetching another chunk
+// across the C boundary if the current one is exhausted. The stream ends
+// when the C-API returns an empty chunk; a nil chunk instead signals a
+// failure, surfaced via Error, unless the C-API's last error message is
+// empty, in which case it also ends the stream. Next returns false in all
+// these cases, so
+// always check Error after the loop to tell a clean end from a failure.
+func (it *Iterator) Next() bool {
+	if it == nil || it.err != nil || it.done {
+		return false
+	}
+	if it.idx >= len(it.buf) {
+		if it.handle == nil {
+			it.done = true
+			return false
+		}
+		var jsonStr *C.char
+		if it.disk {
+			jsonStr = C.agent_mem_db_disk_iterator_next_chunk(it.handle)
+		} else {
+			jsonStr = C.agent_mem_db_iterator_next_chunk(it.handle)
+		}
+		if jsonStr == nil {
+			if msg := lastError(); msg != "" {
+				it.err = fmt.Errorf("iterator chunk failed: %s", msg)
+			} else {
+				it.done = true
+			}
+			return false
+		}
+		s := C.GoString(jsonStr)
+		C.agent_mem_db_free_string(jsonStr)
+		var chunk []Episode
+		if err := json.Unmarshal([]byte(s), &chunk); err != nil {
+			it.err = fmt.Errorf("parse iterator chunk: %w", err)
+			return false
+		}
+		if len(chunk) == 0 {
+			it.done = true
+			return false
+		}
+		it.buf = chunk
+		it.idx = 0
+	}
+	it.cur = it.buf[it.idx]
+	it.idx++
+	it.positioned = true
+	return true
+}
+
+// Valid reports whether Episode returns a usable value, i.e. whether the
+// most recent call to Next returned true. It is false before the first
+// call to Next.
+func (it *Iterator) Valid() bool {
+	return it != nil && it.positioned && it.err == nil && !it.done
+}
+
+// Episode returns the episode at the iterator's current position. Only
+// valid after a call to Next that returned true.
+func (it *Iterator) Episode() Episode {
+	return it.cur
+}
+
+// Error returns the first error encountered by the iterator, if any.
+func (it *Iterator) Error() error {
+	if it == nil {
+		return nil
+	}
+	return it.err
+}
+
+// Release releases the iterator's resources. Call when done.
+func (it *Iterator) Release() {
+	if it == nil || it.handle == nil {
+		return
+	}
+	if it.disk {
+		C.agent_mem_db_disk_iterator_release(it.handle)
+	} else {
+		C.agent_mem_db_iterator_release(it.handle)
+	}
+	it.handle = nil
+}
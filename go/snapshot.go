@@ -0,0 +1,87 @@
+package agentmemdb
+
+/*
+#include "agent_mem_db.h"
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"unsafe"
+)
+
+// Snapshot is a logical point-in-time view of a DB or DiskDB's index. A
+// reader can run a series of Query calls against a Snapshot while other
+// goroutines keep calling Store or Prune* without seeing partial updates
+// or torn reads. Snapshots pin the underlying data until Release is
+// called; pruning becomes a soft-delete that only reclaims space once no
+// live snapshot still references it.
+type Snapshot struct {
+	handle C.AgentMemDBSnapshotHandle
+	dim    int
+}
+
+// GetSnapshot captures a point-in-time view of the database. Call
+// Release when done.
+func (db *DB) GetSnapshot() (*Snapshot, error) {
+	if db == nil || db.handle == nil {
+		return nil, fmt.Errorf("db is nil or freed")
+	}
+	h := C.agent_mem_db_snapshot_new(db.handle)
+	if h == nil {
+		return nil, fmt.Errorf("get snapshot failed: %s", lastError())
+	}
+	return &Snapshot{handle: h, dim: db.dim}, nil
+}
+
+// GetSnapshot captures a point-in-time view of the database. Call
+// Release when done.
+func (db *DiskDB) GetSnapshot() (*Snapshot, error) {
+	if db == nil || db.handle == nil {
+		return nil, fmt.Errorf("db is nil or freed")
+	}
+	h := C.agent_mem_db_disk_snapshot_new(db.handle)
+	if h == nil {
+		return nil, fmt.Errorf("get snapshot failed: %s", lastError())
+	}
+	return &Snapshot{handle: h, dim: db.dim}, nil
+}
+
+// Dim returns the embedding dimension.
+func (snap *Snapshot) Dim() int {
+	return snap.dim
+}
+
+// Query returns similar episodes as they existed when the snapshot was
+// taken.
+func (snap *Snapshot) Query(embedding []float32, minReward float32, topK int) ([]Episode, error) {
+	if snap == nil || snap.handle == nil {
+		return nil, fmt.Errorf("snapshot is nil or released")
+	}
+	if len(embedding) != snap.dim {
+		return nil, fmt.Errorf("embedding dimension mismatch: expected %d, got %d", snap.dim, len(embedding))
+	}
+	emb := (*C.float)(unsafe.Pointer(&embedding[0]))
+	jsonStr := C.agent_mem_db_snapshot_query(snap.handle, emb, C.size_t(len(embedding)), C.float(minReward), C.size_t(topK))
+	if jsonStr == nil {
+		return nil, fmt.Errorf("query failed: %s", lastError())
+	}
+	defer C.agent_mem_db_free_string(jsonStr)
+	s := C.GoString(jsonStr)
+	var episodes []Episode
+	if err := json.Unmarshal([]byte(s), &episodes); err != nil {
+		return nil, fmt.Errorf("parse query result: %w", err)
+	}
+	return episodes, nil
+}
+
+// Release releases the snapshot, allowing space from episodes pruned
+// since it was taken to be reclaimed once no other snapshot references
+// them.
+func (snap *Snapshot) Release() {
+	if snap != nil && snap.handle != nil {
+		C.agent_mem_db_snapshot_release(snap.handle)
+		snap.handle = nil
+	}
+}
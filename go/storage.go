@@ -0,0 +1,307 @@
+package agentmemdb
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// File is a single named file within a Storage.
+type File interface {
+	io.ReadWriteCloser
+	Sync() error
+}
+
+// Storage abstracts where a DiskDB's episode log, checkpoints, and
+// journal files live, modeled on goleveldb's storage.Storage. The
+// default, FileStorage, is backed by a directory on the local
+// filesystem; MemStorage keeps its files in process memory. A DiskDB
+// opened on MemStorage still works in a scratch directory on disk (see
+// below). Implement Storage to bolt on a different durability tier (S3,
+// encrypted-at-rest, tmpfs, ...).
+//
+// The C-API underneath DiskDB only operates on a real filesystem
+// directory. OpenDisk* hands a FileStorage's directory straight to the
+// C-API; any other Storage is staged into a scratch directory that the
+// C-API uses directly, and that scratch directory is flushed back to
+// the Storage on Checkpoint and CompactNow. Free does not flush; call
+// Checkpoint first if durability against such a Storage is required.
+type Storage interface {
+	// OpenFile opens (creating it if necessary) the named file for
+	// reading and writing from the start.
+	OpenFile(name string) (File, error)
+	// Remove deletes the named file, if present.
+	Remove(name string) error
+	// List returns the names of all files currently in this storage.
+	List() ([]string, error)
+	// Close releases any resources held by this storage.
+	Close() error
+}
+
+// dirStorage is implemented by Storage backends that are already a real
+// filesystem directory, letting OpenDisk* hand that directory straight
+// to the C-API instead of staging through a scratch copy.
+type dirStorage interface {
+	dir() string
+}
+
+// FileStorage is the default Storage, backed by a directory on the
+// local filesystem.
+type FileStorage struct {
+	root string
+}
+
+// NewFileStorage returns a Storage backed by the given directory, which
+// is created if it does not already exist.
+func NewFileStorage(dir string) (*FileStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create storage dir: %w", err)
+	}
+	return &FileStorage{root: dir}, nil
+}
+
+func (s *FileStorage) dir() string { return s.root }
+
+// OpenFile opens the named file under this storage's directory, creating
+// it if necessary.
+func (s *FileStorage) OpenFile(name string) (File, error) {
+	f, err := os.OpenFile(filepath.Join(s.root, name), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", name, err)
+	}
+	return f, nil
+}
+
+// Remove deletes the named file, if present.
+func (s *FileStorage) Remove(name string) error {
+	if err := os.Remove(filepath.Join(s.root, name)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove %s: %w", name, err)
+	}
+	return nil
+}
+
+// List returns the names of all files in this storage's directory.
+func (s *FileStorage) List() ([]string, error) {
+	entries, err := os.ReadDir(s.root)
+	if err != nil {
+		return nil, fmt.Errorf("list storage dir: %w", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+// Close is a no-op: FileStorage does not own the directory it was given
+// and leaves it in place.
+func (s *FileStorage) Close() error {
+	return nil
+}
+
+// MemStorage is a Storage backed entirely by process memory: every file
+// is a byte buffer living in a Go map, and MemStorage itself makes no
+// filesystem access. A DiskDB opened on a MemStorage copies its files
+// into a scratch directory on disk and works there (see Storage).
+type MemStorage struct {
+	mu    sync.Mutex
+	files map[string]*memFile
+}
+
+// NewMemStorage returns an empty, in-memory Storage.
+func NewMemStorage() *MemStorage {
+	return &MemStorage{files: make(map[string]*memFile)}
+}
+
+// OpenFile returns a handle to the named in-memory file, creating it if
+// necessary.
+func (s *MemStorage) OpenFile(name string) (File, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.files == nil {
+		return nil, fmt.Errorf("storage is closed")
+	}
+	f, ok := s.files[name]
+	if !ok {
+		f = &memFile{}
+		s.files[name] = f
+	}
+	return &memFileHandle{file: f}, nil
+}
+
+// Remove deletes the named in-memory file, if present.
+func (s *MemStorage) Remove(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.files, name)
+	return nil
+}
+
+// List returns the names of all in-memory files currently held.
+func (s *MemStorage) List() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.files))
+	for name := range s.files {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// Close discards all in-memory files.
+func (s *MemStorage) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files = nil
+	return nil
+}
+
+// memFile is the buffer backing one named MemStorage file. Every handle
+// opened for the same name shares it.
+type memFile struct {
+	mu   sync.Mutex
+	data []byte
+}
+
+// memFileHandle is a File view over a memFile, reading and writing from
+// its own independent offset.
+type memFileHandle struct {
+	file *memFile
+	pos  int
+}
+
+func (h *memFileHandle) Read(p []byte) (int, error) {
+	h.file.mu.Lock()
+	defer h.file.mu.Unlock()
+	if h.pos >= len(h.file.data) {
+		return 0, io.EOF
+	}
+	n := copy(p, h.file.data[h.pos:])
+	h.pos += n
+	return n, nil
+}
+
+func (h *memFileHandle) Write(p []byte) (int, error) {
+	h.file.mu.Lock()
+	defer h.file.mu.Unlock()
+	end := h.pos + len(p)
+	if end > len(h.file.data) {
+		grown := make([]byte, end)
+		copy(grown, h.file.data)
+		h.file.data = grown
+	}
+	copy(h.file.data[h.pos:end], p)
+	h.pos = end
+	return len(p), nil
+}
+
+// Sync is a no-op: memFileHandle writes are already visible to every
+// other handle on the same memFile.
+func (h *memFileHandle) Sync() error {
+	return nil
+}
+
+// Close is a no-op; the underlying memFile outlives the handle.
+func (h *memFileHandle) Close() error {
+	return nil
+}
+
+// stageDir resolves storage to a real filesystem directory for the
+// C-API to use. A Storage that is already a real directory (FileStorage)
+// is used as-is; anything else is staged into a fresh scratch directory
+// populated with a copy of storage's current files. staged reports
+// whether the returned directory must be flushed back to storage later
+// via flushStage.
+func stageDir(storage Storage) (dir string, staged bool, err error) {
+	if ds, ok := storage.(dirStorage); ok {
+		return ds.dir(), false, nil
+	}
+	scratch, err := os.MkdirTemp("", "agent_mem_db_stage_*")
+	if err != nil {
+		return "", false, fmt.Errorf("create stage dir: %w", err)
+	}
+	names, err := storage.List()
+	if err != nil {
+		os.RemoveAll(scratch)
+		return "", false, fmt.Errorf("list storage: %w", err)
+	}
+	for _, name := range names {
+		if err := copyStorageFileToDir(storage, name, scratch); err != nil {
+			os.RemoveAll(scratch)
+			return "", false, err
+		}
+	}
+	return scratch, true, nil
+}
+
+func copyStorageFileToDir(storage Storage, name, dir string) error {
+	src, err := storage.OpenFile(name)
+	if err != nil {
+		return fmt.Errorf("open storage file %s: %w", name, err)
+	}
+	data, err := io.ReadAll(src)
+	src.Close()
+	if err != nil {
+		return fmt.Errorf("read storage file %s: %w", name, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+		return fmt.Errorf("write stage file %s: %w", name, err)
+	}
+	return nil
+}
+
+// flushStage copies a staged directory's current files back into
+// storage, removing any storage files no longer present. It is a no-op
+// when dir wasn't staged (i.e. storage was already a real directory).
+func flushStage(dir string, storage Storage, staged bool) error {
+	if !staged {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("list stage dir: %w", err)
+	}
+	seen := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := e.Name()
+		seen[name] = true
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return fmt.Errorf("read stage file %s: %w", name, err)
+		}
+		if err := storage.Remove(name); err != nil {
+			return fmt.Errorf("clear storage file %s: %w", name, err)
+		}
+		dst, err := storage.OpenFile(name)
+		if err != nil {
+			return fmt.Errorf("open storage file %s: %w", name, err)
+		}
+		_, werr := dst.Write(data)
+		cerr := dst.Close()
+		if werr != nil {
+			return fmt.Errorf("write storage file %s: %w", name, werr)
+		}
+		if cerr != nil {
+			return fmt.Errorf("close storage file %s: %w", name, cerr)
+		}
+	}
+	names, err := storage.List()
+	if err != nil {
+		return fmt.Errorf("list storage: %w", err)
+	}
+	for _, name := range names {
+		if !seen[name] {
+			if err := storage.Remove(name); err != nil {
+				return fmt.Errorf("remove stale storage file %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}